@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a single cached HTTP response body plus the validators
+// needed to revalidate it once it goes stale.
+type CacheEntry struct {
+	Body         []byte
+	ETag         string
+	LastModified string
+	ExpiresAt    time.Time
+}
+
+// Cache stores CacheEntry values by request URL. LRUCache is the default
+// in-memory implementation; a disk- or Redis-backed store can implement
+// this instead without CachingTransport changing.
+type Cache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+}
+
+// LRUCache is a fixed-capacity, in-memory Cache that evicts the
+// least-recently-used entry once full.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+type lruItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// NewLRUCache builds an LRUCache holding at most capacity entries.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *LRUCache) Get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+
+	c.ll.MoveToFront(el)
+	return el.Value.(*lruItem).entry, true
+}
+
+func (c *LRUCache) Set(key string, entry CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruItem).entry = entry
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	el := c.ll.PushFront(&lruItem{key: key, entry: entry})
+	c.items[key] = el
+
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruItem).key)
+		}
+	}
+}
+
+// CachingTransport wraps an http.RoundTripper with a Cache, honoring
+// Cache-Control/Expires for freshness and revalidating stale entries with
+// If-None-Match/If-Modified-Since. NWS asks API clients to cache responses
+// this way rather than polling on every call.
+type CachingTransport struct {
+	Next  http.RoundTripper
+	Cache Cache
+}
+
+// NewCachingTransport wraps http.DefaultTransport with cache.
+func NewCachingTransport(cache Cache) *CachingTransport {
+	return &CachingTransport{Next: http.DefaultTransport, Cache: cache}
+}
+
+func (t *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.Next.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	entry, hasEntry := t.Cache.Get(key)
+	if hasEntry && time.Now().Before(entry.ExpiresAt) {
+		return cachedResponse(req, entry), nil
+	}
+
+	revalReq := req.Clone(req.Context())
+	if hasEntry {
+		if entry.ETag != "" {
+			revalReq.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			revalReq.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := t.Next.RoundTrip(revalReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasEntry && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+
+		// A 304 has no body, but its headers carry fresh validators and
+		// freshness lifetime; refresh the stored entry from them so it
+		// doesn't revalidate against NWS again on every call forever.
+		refreshed := entry
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			refreshed.ETag = etag
+		}
+		if lastModified := resp.Header.Get("Last-Modified"); lastModified != "" {
+			refreshed.LastModified = lastModified
+		}
+		refreshed.ExpiresAt = expiryFromHeaders(resp.Header)
+
+		if isCacheable(resp.Header) {
+			t.Cache.Set(key, refreshed)
+		}
+
+		return cachedResponse(req, refreshed), nil
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if isCacheable(resp.Header) {
+			t.Cache.Set(key, CacheEntry{
+				Body:         body,
+				ETag:         resp.Header.Get("ETag"),
+				LastModified: resp.Header.Get("Last-Modified"),
+				ExpiresAt:    expiryFromHeaders(resp.Header),
+			})
+		}
+
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	return resp, nil
+}
+
+func cachedResponse(req *http.Request, entry CacheEntry) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     http.StatusText(http.StatusOK),
+		Body:       io.NopCloser(bytes.NewReader(entry.Body)),
+		Header:     http.Header{},
+		Request:    req,
+	}
+}
+
+// isCacheable reports whether a response is allowed to be stored, per its
+// Cache-Control directives. NWS responses are cooperative about this today,
+// but Cache is swappable to other backends (Redis, disk) that won't share
+// its behavior, so this needs to hold regardless of what's storing it.
+func isCacheable(header http.Header) bool {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		switch strings.TrimSpace(directive) {
+		case "no-store", "no-cache", "private":
+			return false
+		}
+	}
+	return true
+}
+
+// expiryFromHeaders derives when a cached response should be revalidated,
+// preferring Cache-Control's max-age over Expires.
+func expiryFromHeaders(header http.Header) time.Time {
+	if cc := header.Get("Cache-Control"); cc != "" {
+		for _, directive := range strings.Split(cc, ",") {
+			directive = strings.TrimSpace(directive)
+			if seconds, ok := strings.CutPrefix(directive, "max-age="); ok {
+				if n, err := strconv.Atoi(seconds); err == nil {
+					return time.Now().Add(time.Duration(n) * time.Second)
+				}
+			}
+		}
+	}
+
+	if expires := header.Get("Expires"); expires != "" {
+		if t, err := http.ParseTime(expires); err == nil {
+			return t
+		}
+	}
+
+	return time.Now()
+}
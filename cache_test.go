@@ -0,0 +1,161 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubTransport returns the next response from responses on each RoundTrip
+// call, in order, and records every request it was given.
+type stubTransport struct {
+	responses []*http.Response
+	requests  []*http.Request
+}
+
+func (s *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.requests = append(s.requests, req)
+	resp := s.responses[len(s.requests)-1]
+	resp.Request = req
+	return resp, nil
+}
+
+// newResponse builds a canned response. headers is a flat list of
+// alternating key/value pairs, set via Header.Set so keys end up
+// MIME-canonicalized the way a real net/http response would have them.
+func newResponse(status int, body string, headers ...string) *http.Response {
+	header := http.Header{}
+	for i := 0; i+1 < len(headers); i += 2 {
+		header.Set(headers[i], headers[i+1])
+	}
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestCachingTransport_FreshHit(t *testing.T) {
+	stub := &stubTransport{
+		responses: []*http.Response{
+			newResponse(http.StatusOK, "first", "Cache-Control", "max-age=60"),
+		},
+	}
+	transport := &CachingTransport{Next: stub, Cache: NewLRUCache(10)}
+
+	for i := 0; i < 3; i++ {
+		resp, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com/forecast", nil))
+		if err != nil {
+			t.Fatalf("RoundTrip: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if string(body) != "first" {
+			t.Errorf("call %d: body = %q, want %q", i, body, "first")
+		}
+	}
+
+	if len(stub.requests) != 1 {
+		t.Errorf("got %d upstream requests, want 1 (later calls should be served from cache)", len(stub.requests))
+	}
+}
+
+func TestCachingTransport_StaleRevalidates(t *testing.T) {
+	stub := &stubTransport{
+		responses: []*http.Response{
+			newResponse(http.StatusOK, "first", "Cache-Control", "max-age=0", "ETag", `"v1"`),
+			newResponse(http.StatusOK, "second", "Cache-Control", "max-age=0", "ETag", `"v2"`),
+		},
+	}
+	transport := &CachingTransport{Next: stub, Cache: NewLRUCache(10)}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/forecast", nil)
+
+	resp, _ := transport.RoundTrip(req)
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "first" {
+		t.Fatalf("first call body = %q, want %q", body, "first")
+	}
+
+	resp, _ = transport.RoundTrip(req)
+	body, _ = io.ReadAll(resp.Body)
+	if string(body) != "second" {
+		t.Fatalf("second call body = %q, want %q (entry was already stale, max-age=0)", body, "second")
+	}
+
+	if len(stub.requests) != 2 {
+		t.Fatalf("got %d upstream requests, want 2", len(stub.requests))
+	}
+	if got := stub.requests[1].Header.Get("If-None-Match"); got != `"v1"` {
+		t.Errorf("revalidation request If-None-Match = %q, want %q", got, `"v1"`)
+	}
+}
+
+func TestCachingTransport_NotModifiedRefreshesEntry(t *testing.T) {
+	cache := NewLRUCache(10)
+	stub := &stubTransport{
+		responses: []*http.Response{
+			newResponse(http.StatusOK, "first", "Cache-Control", "max-age=0", "ETag", `"v1"`),
+			newResponse(http.StatusNotModified, "", "Cache-Control", "max-age=60", "ETag", `"v1"`),
+		},
+	}
+	transport := &CachingTransport{Next: stub, Cache: cache}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/forecast", nil)
+
+	transport.RoundTrip(req)
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "first" {
+		t.Fatalf("304 response body = %q, want the cached body %q", body, "first")
+	}
+
+	entry, ok := cache.Get(req.URL.String())
+	if !ok {
+		t.Fatal("expected an entry to remain cached after the 304")
+	}
+	if !entry.ExpiresAt.After(time.Now()) {
+		t.Errorf("ExpiresAt = %v, want a time in the future (the 304's max-age=60 should refresh it)", entry.ExpiresAt)
+	}
+
+	// A third call within the refreshed TTL must not hit upstream again.
+	transport.RoundTrip(req)
+	if len(stub.requests) != 2 {
+		t.Errorf("got %d upstream requests, want 2 (third call should be served from the refreshed entry)", len(stub.requests))
+	}
+}
+
+func TestCachingTransport_NoStoreIsNotCached(t *testing.T) {
+	stub := &stubTransport{
+		responses: []*http.Response{
+			newResponse(http.StatusOK, "first", "Cache-Control", "no-store"),
+			newResponse(http.StatusOK, "second", "Cache-Control", "no-store"),
+		},
+	}
+	transport := &CachingTransport{Next: stub, Cache: NewLRUCache(10)}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/forecast", nil)
+
+	resp, _ := transport.RoundTrip(req)
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "first" {
+		t.Fatalf("first call body = %q, want %q", body, "first")
+	}
+
+	resp, _ = transport.RoundTrip(req)
+	body, _ = io.ReadAll(resp.Body)
+	if string(body) != "second" {
+		t.Fatalf("second call body = %q, want %q (no-store response should never be served from cache)", body, "second")
+	}
+
+	if len(stub.requests) != 2 {
+		t.Errorf("got %d upstream requests, want 2 (no-store must bypass the cache)", len(stub.requests))
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Dispatcher routes JSON-RPC 2.0 requests to the MCP methods the server
+// understands, independent of the transport (HTTP or stdio) carrying them.
+type Dispatcher struct {
+	registry *ToolRegistry
+}
+
+// NewDispatcher builds a Dispatcher over the given registry.
+func NewDispatcher(registry *ToolRegistry) *Dispatcher {
+	return &Dispatcher{registry: registry}
+}
+
+// Dispatch handles a single JSON-RPC request and returns the response to
+// send back, or nil if req is a notification that must not be responded to.
+func (d *Dispatcher) Dispatch(ctx context.Context, req JSONRPCRequest) *JSONRPCResponse {
+	switch req.Method {
+	case "initialize":
+		return d.handleInitialize(req)
+	case "notifications/initialized":
+		return nil
+	case "ping":
+		return newResult(req.ID, struct{}{})
+	case "tools/list":
+		return newResult(req.ID, ToolListResult{Tools: d.registry.List()})
+	case "tools/call":
+		return d.handleToolsCall(ctx, req)
+	default:
+		if req.isNotification() {
+			return nil
+		}
+		return newError(req.ID, ErrCodeMethodNotFound, "method not found: "+req.Method, nil)
+	}
+}
+
+func (d *Dispatcher) handleInitialize(req JSONRPCRequest) *JSONRPCResponse {
+	var params struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return newError(req.ID, ErrCodeInvalidParams, "invalid initialize params: "+err.Error(), nil)
+		}
+	}
+
+	protocolVersion := params.ProtocolVersion
+	if protocolVersion == "" {
+		protocolVersion = "2024-11-05"
+	}
+
+	capabilities := Capabilities{}
+	capabilities.Tools.ListChanged = true
+
+	return newResult(req.ID, Result{
+		ProtocolVersion: protocolVersion,
+		Capabilities:    capabilities,
+		ServerInfo: ServerInfo{
+			Name:    "hermes-planner",
+			Version: "0.0.1",
+		},
+	})
+}
+
+func (d *Dispatcher) handleToolsCall(ctx context.Context, req JSONRPCRequest) *JSONRPCResponse {
+	var params struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return newError(req.ID, ErrCodeInvalidParams, "invalid tools/call params: "+err.Error(), nil)
+	}
+
+	ctx, span := startToolSpan(ctx, params.Name)
+	defer span.End()
+	ctx = withToolLogger(ctx, params.Name, req.ID)
+
+	start := time.Now()
+	res, err := d.registry.Call(ctx, params.Name, params.Arguments)
+	observeToolCall(params.Name, start, err)
+
+	if err != nil {
+		if errors.Is(err, ErrToolNotFound) {
+			return newError(req.ID, ErrCodeToolNotFound, err.Error(), nil)
+		}
+		return newError(req.ID, ErrCodeToolExecution, err.Error(), nil)
+	}
+
+	return newResult(req.ID, res)
+}
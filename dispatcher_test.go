@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func echoTool() Tool {
+	return Tool{
+		Name:        "echo",
+		Description: "echoes its input",
+		InputSchema: ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"msg": map[string]interface{}{"type": "string"},
+			},
+			Required: []string{"msg"},
+		},
+		Handler: func(ctx context.Context, args ToolRunParams) (*ToolResult, error) {
+			return &ToolResult{Content: []TextContent{{Type: "text", Text: args.Args["msg"].(string)}}}, nil
+		},
+	}
+}
+
+func newTestDispatcher(t *testing.T) *Dispatcher {
+	t.Helper()
+	registry := NewToolRegistry()
+	if err := registry.Register(echoTool()); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	return NewDispatcher(registry)
+}
+
+func TestDispatcher_Initialize(t *testing.T) {
+	d := newTestDispatcher(t)
+
+	req := JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "initialize", Params: json.RawMessage(`{"protocolVersion":"2024-11-05"}`)}
+	resp := d.Dispatch(context.Background(), req)
+
+	if resp == nil {
+		t.Fatal("expected a response to a request with an ID")
+	}
+	result, ok := resp.Result.(Result)
+	if !ok {
+		t.Fatalf("Result is %T, want Result", resp.Result)
+	}
+	if result.ProtocolVersion != "2024-11-05" {
+		t.Errorf("ProtocolVersion = %q, want the client's echoed version", result.ProtocolVersion)
+	}
+}
+
+func TestDispatcher_NotificationGetsNoResponse(t *testing.T) {
+	d := newTestDispatcher(t)
+
+	req := JSONRPCRequest{JSONRPC: "2.0", Method: "notifications/initialized"}
+	if resp := d.Dispatch(context.Background(), req); resp != nil {
+		t.Errorf("expected nil response to a notification, got %+v", resp)
+	}
+
+	req = JSONRPCRequest{JSONRPC: "2.0", Method: "some/unknown/method"}
+	if resp := d.Dispatch(context.Background(), req); resp != nil {
+		t.Errorf("expected nil response to an unknown-method notification (no ID), got %+v", resp)
+	}
+}
+
+func TestDispatcher_ToolsCall(t *testing.T) {
+	d := newTestDispatcher(t)
+
+	req := JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      float64(2),
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"echo","arguments":{"msg":"hi"}}`),
+	}
+	resp := d.Dispatch(context.Background(), req)
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %+v", resp.Error)
+	}
+	result, ok := resp.Result.(*ToolResult)
+	if !ok {
+		t.Fatalf("Result is %T, want *ToolResult", resp.Result)
+	}
+	if len(result.Content) != 1 || result.Content[0].Text != "hi" {
+		t.Errorf("Content = %+v, want a single \"hi\" entry", result.Content)
+	}
+}
+
+func TestDispatcher_ToolsCallUnknownTool(t *testing.T) {
+	d := newTestDispatcher(t)
+
+	req := JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      float64(3),
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"does-not-exist","arguments":{}}`),
+	}
+	resp := d.Dispatch(context.Background(), req)
+
+	if resp.Error == nil {
+		t.Fatal("expected an error calling an unregistered tool")
+	}
+	if resp.Error.Code != ErrCodeToolNotFound {
+		t.Errorf("Error.Code = %d, want %d (ErrCodeToolNotFound)", resp.Error.Code, ErrCodeToolNotFound)
+	}
+}
+
+func TestDispatcher_MethodNotFound(t *testing.T) {
+	d := newTestDispatcher(t)
+
+	req := JSONRPCRequest{JSONRPC: "2.0", ID: float64(4), Method: "not/a/real/method"}
+	resp := d.Dispatch(context.Background(), req)
+
+	if resp.Error == nil {
+		t.Fatal("expected an error for an unknown method with an ID")
+	}
+	if resp.Error.Code != ErrCodeMethodNotFound {
+		t.Errorf("Error.Code = %d, want %d (ErrCodeMethodNotFound)", resp.Error.Code, ErrCodeMethodNotFound)
+	}
+}
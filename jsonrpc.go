@@ -0,0 +1,61 @@
+package main
+
+import "encoding/json"
+
+// Standard JSON-RPC 2.0 error codes, per the spec.
+const (
+	ErrCodeParseError     = -32700
+	ErrCodeInvalidRequest = -32600
+	ErrCodeMethodNotFound = -32601
+	ErrCodeInvalidParams  = -32602
+	ErrCodeInternalError  = -32603
+)
+
+// MCP-specific tool errors, in the -32000 to -32099 implementation-defined
+// server error range reserved by the JSON-RPC spec.
+const (
+	ErrCodeToolNotFound  = -32001
+	ErrCodeToolExecution = -32002
+)
+
+// JSONRPCRequest is a single JSON-RPC 2.0 request or notification envelope.
+// A request with no ID is a notification and must not receive a response.
+type JSONRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// JSONRPCResponse is a single JSON-RPC 2.0 response envelope.
+type JSONRPCResponse struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      interface{}   `json:"id,omitempty"`
+	Result  interface{}   `json:"result,omitempty"`
+	Error   *JSONRPCError `json:"error,omitempty"`
+}
+
+// JSONRPCError is the "error" member of a JSON-RPC 2.0 response.
+type JSONRPCError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func newResult(id interface{}, result interface{}) *JSONRPCResponse {
+	return &JSONRPCResponse{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+func newError(id interface{}, code int, message string, data interface{}) *JSONRPCResponse {
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &JSONRPCError{Code: code, Message: message, Data: data},
+	}
+}
+
+// isNotification reports whether req carries no ID, meaning it is a
+// notification per the JSON-RPC 2.0 spec and must not be responded to.
+func (req JSONRPCRequest) isNotification() bool {
+	return req.ID == nil
+}
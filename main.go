@@ -3,74 +3,87 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"flag"
 	"log/slog"
 	"net/http"
+	"os"
 	"strings"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// nwsErrorMessage turns the errors returned by each tried provider into an
+// actionable message for the end user. A typed *NWSError is distinguished
+// into "no data for this area" vs. "NWS is down"; any other provider's
+// error is surfaced as-is instead of being relabeled an NWS failure, since
+// it usually isn't one (e.g. OpenWeatherMap rejecting an unset API key).
+func nwsErrorMessage(errs []error, notFoundMsg string) string {
+	if len(errs) == 0 {
+		return "Failed to retrieve data"
+	}
+
+	reasons := make([]string, len(errs))
+	for i, err := range errs {
+		var nwsErr *NWSError
+		switch {
+		case errors.As(err, &nwsErr) && nwsErr.Status == http.StatusNotFound:
+			reasons[i] = notFoundMsg
+		case errors.As(err, &nwsErr) && nwsErr.Status >= http.StatusInternalServerError:
+			reasons[i] = "NWS is temporarily unavailable, try again"
+		default:
+			reasons[i] = err.Error()
+		}
+	}
+	return strings.Join(reasons, "; ")
+}
+
 func main() {
-	weatherTool := Tool{
-		Name:        "get-forecast",
-		Description: "Get weather alerts for a state",
-		InputSchema: ToolInputSchema{
-			Type: "object",
-			Properties: map[string]interface{}{
-				"state": map[string]interface{}{
-					"type":        "string",
-					"description": "The state to get weather alerts for",
-				},
-			},
-			Required: []string{"state"},
-		},
-		Handler: func(ctx context.Context, args ToolRunParams) (*ToolResult, error) {
-			state := args.Args["state"].(string)
-			weatherURL := "https://api.weather.gov/alerts?area=" + state
-
-			data, err := GetAlerts(weatherURL)
-
-			if err != nil {
-				slog.Error("error getting alerts", "Err", err)
-				return &ToolResult{
-					Content: []TextContent{
-						{Type: "text", Text: "Failed to retrieve alerts data"},
-					},
-				}, nil
-			}
-
-			if len(data) == 0 {
-				return &ToolResult{
-					Content: []TextContent{
-						{Type: "text", Text: "No active alerts for " + state},
-					},
-				}, nil
-			}
-
-			formattedAlerts := make([]string, len(data))
-			for i, f := range data {
-				formattedAlerts[i] = FormatAlert(f)
-			}
-
-			// Build a properly formatted alert text with state name and all formatted alerts
-			alertText := fmt.Sprintf("Active alerts for %s:\n\n%s", state, strings.Join(formattedAlerts, "\n"))
-
-			return &ToolResult{
-				Content: []TextContent{
-					{Type: "text", Text: alertText},
-				},
-			}, nil
-		},
+	transport := flag.String("transport", "http", "MCP transport to serve: http or stdio")
+	flag.Parse()
+
+	ctx := context.Background()
+	shutdownTracing, err := initTracing(ctx)
+	if err != nil {
+		slog.Error("failed to init tracing", "err", err)
+		os.Exit(1)
 	}
+	defer shutdownTracing(ctx)
 
-	http.ListenAndServe("localhost:4006", handle(HandleMcpParams{Tools: []Tool{weatherTool}}))
+	providers := []WeatherProvider{NWSProvider{}}
+	if owm := NewOpenWeatherMapProvider(); owm.APIKey != "" {
+		providers = append(providers, owm)
+	} else {
+		slog.Warn("OWM_API_KEY not set; OpenWeatherMap fallback disabled, NWS-only coverage")
+	}
+
+	registry := NewToolRegistry()
+	for _, t := range NewWeatherTools(providers...) {
+		if err := registry.Register(t); err != nil {
+			slog.Error("failed to register tool", "tool", t.Name, "err", err)
+			os.Exit(1)
+		}
+	}
+
+	switch *transport {
+	case "stdio":
+		if err := serveStdio(ctx, registry); err != nil {
+			slog.Error("stdio transport exited", "err", err)
+			os.Exit(1)
+		}
+	case "http":
+		http.ListenAndServe("localhost:4006", handle(HandleMcpParams{Registry: registry}))
+	default:
+		slog.Error("unknown transport", "transport", *transport)
+		os.Exit(1)
+	}
 }
 
 type HandleMcpParams struct {
-	Tools []Tool
+	Registry *ToolRegistry
 }
 
 func handle(hmp HandleMcpParams) http.Handler {
-
 	mux := http.NewServeMux()
 	// Add a health check endpoint
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -78,7 +91,8 @@ func handle(hmp HandleMcpParams) http.Handler {
 		w.Write([]byte("OK"))
 	})
 
-	mux.Handle("/mcp/", http.StripPrefix("/mcp", handleMcp(hmp.Tools...)))
+	mux.Handle("/mcp", handleMcp(hmp.Registry))
+	mux.Handle("/metrics", promhttp.Handler())
 
 	return mux
 }
@@ -108,101 +122,32 @@ type Result struct {
 	ServerInfo      ServerInfo   `json:"serverInfo"`
 }
 
-type ServerInitialization struct {
-	JSONRPC string `json:"jsonrpc"`
-	ID      int    `json:"id"`
-	Result  Result `json:"result"`
-}
-
-func handleMcp(tools ...Tool) http.Handler {
-	mux := http.NewServeMux()
-	slog.Info("mounting mcp mux")
-	toolMap := make(map[string]Tool)
-	for _, t := range tools {
-		toolMap[t.Name] = t
-	}
+// handleMcp serves the MCP JSON-RPC 2.0 dispatcher over a single endpoint,
+// per the spec's streamable-HTTP transport.
+func handleMcp(registry *ToolRegistry) http.Handler {
+	dispatcher := NewDispatcher(registry)
 
-	mux.HandleFunc("/mcp-health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
-
-	initialize := ServerInitialization{
-		JSONRPC: "2.0",
-		ID:      1,
-		Result: Result{
-			ProtocolVersion: "2024-11-05",
-			Capabilities:    Capabilities{},
-			ServerInfo: ServerInfo{
-				Name:    "hermes-planner",
-				Version: "0.0.1",
-			},
-		},
-	}
-
-	mux.HandleFunc("/initialize", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(200)
-		err := json.NewEncoder(w).Encode(initialize)
-		if err != nil {
-			w.WriteHeader(500)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req JSONRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSONRPC(w, newError(nil, ErrCodeParseError, "invalid JSON: "+err.Error(), nil))
 			return
 		}
-		return
-	})
-
-	mux.HandleFunc("/tools/call", func(w http.ResponseWriter, r *http.Request) {
-		// Parse the incoming JSON request
-		var toolRequest struct {
-			Name      string                 `json:"name"`
-			Arguments map[string]interface{} `json:"arguments"`
-		}
 
-		if err := json.NewDecoder(r.Body).Decode(&toolRequest); err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request format"})
+		resp := dispatcher.Dispatch(r.Context(), req)
+		if resp == nil {
+			// Notifications get no body, per the JSON-RPC 2.0 spec.
+			w.WriteHeader(http.StatusAccepted)
 			return
 		}
 
-		// Check if the requested tool is our weatherTool
-		if tool, ok := toolMap[toolRequest.Name]; ok {
-			// Create arguments structure
-			params := ToolRunParams{
-				Name: toolRequest.Name,
-				Args: toolRequest.Arguments,
-			}
-
-			// Call the tool handler
-			res, err := tool.Run(r.Context(), params)
-			if err != nil {
-				w.WriteHeader(http.StatusInternalServerError)
-				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
-				return
-			}
-
-			// Return a successful response
-			w.WriteHeader(http.StatusOK)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"status": "success",
-				"result": res.Content,
-			})
-		} else {
-			// Tool not found
-			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode(map[string]string{"error": "Tool not found: " + toolRequest.Name})
-		}
-	})
-
-	mux.HandleFunc("/tools/list", func(w http.ResponseWriter, r *http.Request) {
-		toolsList := ToolListResult{tools}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		if err := json.NewEncoder(w).Encode(&toolsList); err != nil {
-			slog.Error("Failed to encode tools list", "error", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(map[string]string{"error": "Failed to generate tools list"})
-			return
-		}
+		writeJSONRPC(w, resp)
 	})
+}
 
-	return mux
+func writeJSONRPC(w http.ResponseWriter, resp *JSONRPCResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.Error("failed to encode JSON-RPC response", "err", err)
+	}
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	toolCallsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcp_tool_calls_total",
+		Help: "Total tool invocations, labeled by tool and outcome status",
+	}, []string{"tool", "status"})
+
+	toolDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "mcp_tool_duration_seconds",
+		Help: "Tool invocation latency in seconds",
+	}, []string{"tool"})
+
+	upstreamRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcp_upstream_requests_total",
+		Help: "Total upstream HTTP requests, labeled by host and status",
+	}, []string{"host", "status"})
+
+	upstreamDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "mcp_upstream_duration_seconds",
+		Help: "Upstream HTTP request latency in seconds",
+	}, []string{"host"})
+)
+
+// observeToolCall records the outcome and latency of a single tool
+// invocation, for the mcp_tool_calls_total / mcp_tool_duration_seconds
+// metrics scraped at /metrics.
+func observeToolCall(tool string, start time.Time, err error) {
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	toolCallsTotal.WithLabelValues(tool, status).Inc()
+	toolDurationSeconds.WithLabelValues(tool).Observe(time.Since(start).Seconds())
+}
+
+// observeUpstreamRequest records the outcome and latency of a single
+// upstream HTTP call made by a WeatherProvider.
+func observeUpstreamRequest(host string, start time.Time, statusCode int) {
+	upstreamRequestsTotal.WithLabelValues(host, strconv.Itoa(statusCode)).Inc()
+	upstreamDurationSeconds.WithLabelValues(host).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ctxKey int
+
+const loggerCtxKey ctxKey = iota
+
+// loggerFromContext returns the request-scoped logger attached by
+// withToolLogger, falling back to slog.Default() so callers (e.g.
+// makeNWSRequest) never need a nil check.
+func loggerFromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerCtxKey).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// withToolLogger attaches a logger carrying tool/request_id/trace_id fields
+// to ctx, so every downstream log line (e.g. in makeNWSRequest) is
+// correlatable back to the tool call that triggered it.
+func withToolLogger(ctx context.Context, tool string, requestID interface{}) context.Context {
+	fields := []any{"tool", tool}
+	if requestID != nil {
+		fields = append(fields, "request_id", requestID)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields, "trace_id", sc.TraceID().String())
+	}
+	return context.WithValue(ctx, loggerCtxKey, slog.Default().With(fields...))
+}
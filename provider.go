@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Alert is a normalized weather alert, independent of which provider it
+// came from.
+type Alert struct {
+	Event    string
+	Area     string
+	Severity string
+	Status   string
+	Headline string
+}
+
+// Forecast is a normalized weather forecast, independent of which provider
+// it came from.
+type Forecast struct {
+	Location string
+	Periods  []ForecastPeriod
+}
+
+// WeatherProvider is a source of weather data. NWSProvider and
+// OpenWeatherMapProvider both implement it, and NewWeatherTools can be
+// handed any number of them (Open-Meteo, Pirate Weather, etc. could be
+// added later) without the tool handlers changing.
+type WeatherProvider interface {
+	Alerts(ctx context.Context, area string) ([]Alert, error)
+	ForecastByCoords(ctx context.Context, lat, lng float64) (*Forecast, error)
+	Geocode(ctx context.Context, query string) (lat, lng float64, err error)
+}
+
+// NWSProvider is the US-only, no-API-key weather.gov backend.
+type NWSProvider struct{}
+
+func (NWSProvider) Alerts(ctx context.Context, area string) ([]Alert, error) {
+	features, err := GetAlerts(ctx, "https://api.weather.gov/alerts?area="+area)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make([]Alert, len(features))
+	for i, f := range features {
+		alerts[i] = Alert{
+			Event:    f.Properties.Event,
+			Area:     f.Properties.AreaDesc,
+			Severity: f.Properties.Severity,
+			Status:   f.Properties.Status,
+			Headline: f.Properties.Headline,
+		}
+	}
+	return alerts, nil
+}
+
+func (NWSProvider) ForecastByCoords(ctx context.Context, lat, lng float64) (*Forecast, error) {
+	point, err := Points(ctx, float32(lat), float32(lng))
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := GetForecast(ctx, point.Forecast)
+	if err != nil {
+		return nil, err
+	}
+
+	location := strings.Trim(strings.TrimSpace(point.City+", "+point.State), ", ")
+	return &Forecast{Location: location, Periods: periods}, nil
+}
+
+func (NWSProvider) Geocode(ctx context.Context, query string) (lat, lng float64, err error) {
+	return 0, 0, fmt.Errorf("NWS does not support geocoding; pass coordinates directly")
+}
+
+// ForecastByCoordsHourly is an NWS-specific extension to WeatherProvider for
+// callers that want hour-by-hour periods instead of the standard ~12-hour
+// periods. Providers that don't implement it are skipped by callers that
+// type-assert for it.
+func (NWSProvider) ForecastByCoordsHourly(ctx context.Context, lat, lng float64) (*Forecast, error) {
+	point, err := Points(ctx, float32(lat), float32(lng))
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := GetForecastHourly(ctx, point.ForecastHourly)
+	if err != nil {
+		return nil, err
+	}
+
+	location := strings.Trim(strings.TrimSpace(point.City+", "+point.State), ", ")
+	return &Forecast{Location: location, Periods: periods}, nil
+}
+
+// OpenWeatherMapProvider is a worldwide fallback for points the NWS doesn't
+// cover. It reads its API key from OWM_API_KEY.
+type OpenWeatherMapProvider struct {
+	APIKey string
+}
+
+// NewOpenWeatherMapProvider builds an OpenWeatherMapProvider using the
+// OWM_API_KEY environment variable.
+func NewOpenWeatherMapProvider() *OpenWeatherMapProvider {
+	return &OpenWeatherMapProvider{APIKey: os.Getenv("OWM_API_KEY")}
+}
+
+func (p *OpenWeatherMapProvider) Alerts(ctx context.Context, area string) ([]Alert, error) {
+	lat, lng, err := parseLatLng(area)
+	if err != nil {
+		return nil, fmt.Errorf("OpenWeatherMap alerts require \"lat,lng\" coordinates: %w", err)
+	}
+
+	var resp struct {
+		Alerts []struct {
+			Event       string `json:"event"`
+			Description string `json:"description"`
+		} `json:"alerts"`
+	}
+	if err := p.get(ctx, "onecall", url.Values{
+		"lat": {strconv.FormatFloat(lat, 'f', -1, 64)},
+		"lon": {strconv.FormatFloat(lng, 'f', -1, 64)},
+	}, &resp); err != nil {
+		return nil, err
+	}
+
+	alerts := make([]Alert, len(resp.Alerts))
+	for i, a := range resp.Alerts {
+		alerts[i] = Alert{Event: a.Event, Headline: a.Description}
+	}
+	return alerts, nil
+}
+
+func (p *OpenWeatherMapProvider) ForecastByCoords(ctx context.Context, lat, lng float64) (*Forecast, error) {
+	var resp struct {
+		City struct {
+			Name    string `json:"name"`
+			Country string `json:"country"`
+		} `json:"city"`
+		List []struct {
+			DtTxt string `json:"dt_txt"`
+			Main  struct {
+				Temp float64 `json:"temp"`
+			} `json:"main"`
+			Weather []struct {
+				Description string `json:"description"`
+			} `json:"weather"`
+			Wind struct {
+				Speed float64 `json:"speed"`
+				Deg   float64 `json:"deg"`
+			} `json:"wind"`
+		} `json:"list"`
+	}
+	if err := p.get(ctx, "forecast", url.Values{
+		"lat": {strconv.FormatFloat(lat, 'f', -1, 64)},
+		"lon": {strconv.FormatFloat(lng, 'f', -1, 64)},
+	}, &resp); err != nil {
+		return nil, err
+	}
+
+	periods := make([]ForecastPeriod, len(resp.List))
+	for i, entry := range resp.List {
+		shortForecast := ""
+		if len(entry.Weather) > 0 {
+			shortForecast = entry.Weather[0].Description
+		}
+		periods[i] = ForecastPeriod{
+			Name:            entry.DtTxt,
+			Temperature:     int(kelvinToFahrenheit(entry.Main.Temp)),
+			TemperatureUnit: "F",
+			WindSpeed:       fmt.Sprintf("%.0f mph", entry.Wind.Speed),
+			WindDirection:   degreesToCompass(entry.Wind.Deg),
+			ShortForecast:   shortForecast,
+		}
+	}
+
+	location := strings.Trim(strings.TrimSpace(resp.City.Name+", "+resp.City.Country), ", ")
+	return &Forecast{Location: location, Periods: periods}, nil
+}
+
+func (p *OpenWeatherMapProvider) Geocode(ctx context.Context, query string) (lat, lng float64, err error) {
+	var resp []struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	}
+	if err := p.getGeo(ctx, query, &resp); err != nil {
+		return 0, 0, err
+	}
+	if len(resp) == 0 {
+		return 0, 0, fmt.Errorf("no geocoding results for %q", query)
+	}
+	return resp[0].Lat, resp[0].Lon, nil
+}
+
+func (p *OpenWeatherMapProvider) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	params.Set("appid", p.APIKey)
+	return getJSON(ctx, fmt.Sprintf("https://api.openweathermap.org/data/2.5/%s?%s", path, params.Encode()), out)
+}
+
+func (p *OpenWeatherMapProvider) getGeo(ctx context.Context, query string, out interface{}) error {
+	params := url.Values{"q": {query}, "limit": {"1"}, "appid": {p.APIKey}}
+	return getJSON(ctx, fmt.Sprintf("https://api.openweathermap.org/geo/1.0/direct?%s", params.Encode()), out)
+}
+
+func getJSON(ctx context.Context, requestURL string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	ctx, span := startUpstreamSpan(ctx, req)
+	defer span.End()
+
+	logger := loggerFromContext(ctx)
+	logger.Debug("making OpenWeatherMap request", "url", redactQuery(req.URL))
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		logger.Error("OpenWeatherMap request failed", "url", requestURL, "err", err)
+		return fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+	observeUpstreamRequest(req.URL.Host, start, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP error! status: %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error parsing JSON: %w", err)
+	}
+
+	return nil
+}
+
+// redactQuery returns u without its query string, so logging a request URL
+// never leaks query params like OpenWeatherMap's appid API key.
+func redactQuery(u *url.URL) string {
+	stripped := *u
+	stripped.RawQuery = ""
+	return stripped.String()
+}
+
+func parseLatLng(s string) (lat, lng float64, err error) {
+	parts := strings.SplitN(s, ",", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("expected \"lat,lng\", got %q", s)
+	}
+
+	lat, err = strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid latitude %q: %w", parts[0], err)
+	}
+
+	lng, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid longitude %q: %w", parts[1], err)
+	}
+
+	return lat, lng, nil
+}
+
+func kelvinToFahrenheit(k float64) float64 {
+	return (k-273.15)*9/5 + 32
+}
+
+func degreesToCompass(deg float64) string {
+	directions := []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+	index := int((deg/22.5)+0.5) % len(directions)
+	return directions[index]
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// ErrToolNotFound is wrapped into the error ToolRegistry.Call returns when
+// name isn't registered, so callers can errors.Is against it.
+var ErrToolNotFound = errors.New("tool not found")
+
+// ToolRegistry holds the live set of tools a server exposes. Each tool's
+// InputSchema is compiled once at registration time against a real JSON
+// Schema implementation, so enum/minimum/maximum/pattern/items and nested
+// object properties/required are enforced, not just the seven base types.
+type ToolRegistry struct {
+	mu       sync.RWMutex
+	tools    map[string]registeredTool
+	onChange func()
+}
+
+type registeredTool struct {
+	tool   Tool
+	schema *jsonschema.Schema
+}
+
+// NewToolRegistry builds an empty ToolRegistry.
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{tools: make(map[string]registeredTool)}
+}
+
+// OnListChanged registers the callback invoked whenever Register or
+// Unregister changes the tool list, so a transport can emit the MCP
+// notifications/tools/list_changed notification.
+func (r *ToolRegistry) OnListChanged(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onChange = fn
+}
+
+// Register compiles tool's InputSchema and adds it to the registry,
+// replacing any existing tool of the same name.
+func (r *ToolRegistry) Register(tool Tool) error {
+	schema, err := compileSchema(tool.Name, tool.InputSchema)
+	if err != nil {
+		return fmt.Errorf("compiling schema for tool %q: %w", tool.Name, err)
+	}
+
+	r.mu.Lock()
+	r.tools[tool.Name] = registeredTool{tool: tool, schema: schema}
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	if onChange != nil {
+		onChange()
+	}
+	return nil
+}
+
+// Unregister removes a tool by name. It is a no-op if the tool isn't
+// registered.
+func (r *ToolRegistry) Unregister(name string) {
+	r.mu.Lock()
+	_, existed := r.tools[name]
+	delete(r.tools, name)
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	if existed && onChange != nil {
+		onChange()
+	}
+}
+
+// List returns the currently registered tools.
+func (r *ToolRegistry) List() []Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tools := make([]Tool, 0, len(r.tools))
+	for _, rt := range r.tools {
+		tools = append(tools, rt.tool)
+	}
+	return tools
+}
+
+// Call validates args against the named tool's compiled schema, coerces
+// whole-number float64 values to int where the schema says "integer" (since
+// encoding/json always decodes JSON numbers as float64), and runs the
+// tool's handler.
+func (r *ToolRegistry) Call(ctx context.Context, name string, args map[string]interface{}) (*ToolResult, error) {
+	r.mu.RLock()
+	rt, ok := r.tools[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrToolNotFound, name)
+	}
+
+	if err := rt.schema.Validate(args); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	coerceIntegers(args, rt.tool.InputSchema)
+
+	return rt.tool.Handler(ctx, ToolRunParams{Name: name, Args: args})
+}
+
+func compileSchema(name string, schema ToolInputSchema) (*jsonschema.Schema, error) {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(name, bytes.NewReader(raw)); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(name)
+}
+
+// coerceIntegers walks a tool's top-level properties and converts any
+// whole-number float64 argument to int where the schema declares
+// "integer", so handlers can type-assert to int instead of every caller
+// having to know encoding/json decodes numbers as float64.
+func coerceIntegers(args map[string]interface{}, schema ToolInputSchema) {
+	for name, propSchemaInterface := range schema.Properties {
+		propSchema, ok := propSchemaInterface.(map[string]interface{})
+		if !ok || propSchema["type"] != "integer" {
+			continue
+		}
+
+		value, exists := args[name]
+		if !exists {
+			continue
+		}
+
+		if f, ok := value.(float64); ok && f == float64(int(f)) {
+			args[name] = int(f)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestToolRegistry_CallCoercesWholeNumberFloatToInt(t *testing.T) {
+	r := NewToolRegistry()
+
+	var gotN int
+	var gotOK bool
+	tool := Tool{
+		Name: "count",
+		InputSchema: ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"n": map[string]interface{}{"type": "integer"},
+			},
+			Required: []string{"n"},
+		},
+		Handler: func(ctx context.Context, args ToolRunParams) (*ToolResult, error) {
+			gotN, gotOK = args.Args["n"].(int)
+			return &ToolResult{}, nil
+		},
+	}
+	if err := r.Register(tool); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	if _, err := r.Call(context.Background(), "count", map[string]interface{}{"n": float64(3)}); err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+
+	if !gotOK {
+		t.Fatal("handler's \"n\" arg was not an int")
+	}
+	if gotN != 3 {
+		t.Errorf("n = %d, want 3", gotN)
+	}
+}
+
+func TestToolRegistry_CallValidatesAgainstSchema(t *testing.T) {
+	r := NewToolRegistry()
+	tool := Tool{
+		Name: "count",
+		InputSchema: ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"n": map[string]interface{}{"type": "integer"},
+			},
+			Required: []string{"n"},
+		},
+		Handler: func(ctx context.Context, args ToolRunParams) (*ToolResult, error) {
+			t.Fatal("handler should not run when required args are missing")
+			return nil, nil
+		},
+	}
+	if err := r.Register(tool); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	if _, err := r.Call(context.Background(), "count", map[string]interface{}{}); err == nil {
+		t.Fatal("expected an error for missing required property \"n\"")
+	}
+}
+
+func TestToolRegistry_CallUnknownTool(t *testing.T) {
+	r := NewToolRegistry()
+
+	_, err := r.Call(context.Background(), "does-not-exist", map[string]interface{}{})
+	if !errors.Is(err, ErrToolNotFound) {
+		t.Fatalf("err = %v, want errors.Is(err, ErrToolNotFound)", err)
+	}
+}
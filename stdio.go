@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// serveStdio runs the MCP JSON-RPC 2.0 dispatcher over stdin/stdout, reading
+// one newline-delimited request per line and writing one newline-delimited
+// response per line, as expected by hosts like Claude Desktop. All logging
+// is redirected to stderr so it never corrupts the protocol stream on
+// stdout.
+func serveStdio(ctx context.Context, registry *ToolRegistry) error {
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+	enc := newSyncEncoder(os.Stdout)
+
+	// The stdio transport holds a live connection to the client for the
+	// lifetime of the process, so a registry change can push
+	// notifications/tools/list_changed unprompted, unlike the request/response
+	// HTTP transport.
+	registry.OnListChanged(func() {
+		if err := enc.Encode(&JSONRPCRequest{JSONRPC: "2.0", Method: "notifications/tools/list_changed"}); err != nil {
+			slog.Error("failed to write tools/list_changed notification", "err", err)
+		}
+	})
+
+	dispatcher := NewDispatcher(registry)
+	return dispatchStdio(ctx, dispatcher, os.Stdin, enc)
+}
+
+// syncEncoder serializes writes to a shared json.Encoder, since
+// notifications can be pushed from a registry change concurrently with the
+// request/response loop's own writes.
+type syncEncoder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func newSyncEncoder(w io.Writer) *syncEncoder {
+	return &syncEncoder{enc: json.NewEncoder(w)}
+}
+
+func (s *syncEncoder) Encode(v interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(v)
+}
+
+func dispatchStdio(ctx context.Context, dispatcher *Dispatcher, in io.Reader, enc *syncEncoder) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req JSONRPCRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			if encErr := enc.Encode(newError(nil, ErrCodeParseError, "invalid JSON: "+err.Error(), nil)); encErr != nil {
+				return fmt.Errorf("error writing parse error response: %w", encErr)
+			}
+			continue
+		}
+
+		resp := dispatcher.Dispatch(ctx, req)
+		if resp == nil {
+			// Notifications get no response, per the JSON-RPC 2.0 spec.
+			continue
+		}
+
+		if err := enc.Encode(resp); err != nil {
+			return fmt.Errorf("error writing response: %w", err)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("hermes-planner")
+
+var traceparentPropagator = propagation.TraceContext{}
+
+// initTracing wires an OTLP/HTTP exporter when OTEL_EXPORTER_OTLP_ENDPOINT is
+// set, so operators can opt into tracing without a code change. The
+// returned shutdown func flushes pending spans on exit; it's a no-op when
+// tracing isn't enabled.
+func initTracing(ctx context.Context) (shutdown func(context.Context) error, err error) {
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error creating OTLP exporter: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(traceparentPropagator)
+
+	return tp.Shutdown, nil
+}
+
+// startToolSpan starts a span covering a single tool invocation.
+func startToolSpan(ctx context.Context, toolName string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "tool."+toolName, trace.WithAttributes(attribute.String("tool", toolName)))
+}
+
+// startUpstreamSpan starts a child span for a single upstream HTTP call and
+// injects the W3C traceparent header into req, so NWS (or any provider)
+// request is correlatable end-to-end with the tool call that triggered it.
+func startUpstreamSpan(ctx context.Context, req *http.Request) (context.Context, trace.Span) {
+	ctx, span := tracer.Start(ctx, "upstream."+req.URL.Host, trace.WithAttributes(
+		attribute.String("http.url", req.URL.String()),
+		attribute.String("http.method", req.Method),
+	))
+	traceparentPropagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	return ctx, span
+}
@@ -1,19 +1,49 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
 )
 
 const USER_AGENT = "Weather Tool (your-email@example.com)"
 
+// nwsClient caches NWS responses across calls: alerts change frequently but
+// forecast grids only update every ~hour, and the tool is invoked fresh on
+// every LLM turn, so a per-request http.Client would hammer NWS needlessly.
+var nwsClient = &http.Client{Transport: NewCachingTransport(NewLRUCache(256))}
+
+// NWSError is a typed error decoded from an RFC 7807 problem-details
+// response, which is what the NWS API returns for non-2xx responses.
+type NWSError struct {
+	Status int
+	Type   string
+	Title  string
+	Detail string
+}
+
+func (e *NWSError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("NWS API error (%d): %s - %s", e.Status, e.Title, e.Detail)
+	}
+	return fmt.Sprintf("NWS API error (%d): %s", e.Status, e.Title)
+}
+
+// problemDetails is the RFC 7807 "application/problem+json" shape NWS
+// returns on non-2xx responses.
+type problemDetails struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
 // makeNWSRequest makes a request to the NWS API
-func makeNWSRequest(url string) (map[string]interface{}, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", url, nil)
+func makeNWSRequest(ctx context.Context, url string) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
@@ -21,21 +51,30 @@ func makeNWSRequest(url string) (map[string]interface{}, error) {
 	req.Header.Set("User-Agent", USER_AGENT)
 	req.Header.Set("Accept", "application/geo+json")
 
-	resp, err := client.Do(req)
+	ctx, span := startUpstreamSpan(ctx, req)
+	defer span.End()
+
+	logger := loggerFromContext(ctx)
+	logger.Debug("making NWS request", "url", url)
+
+	start := time.Now()
+	resp, err := nwsClient.Do(req)
 	if err != nil {
+		logger.Error("NWS request failed", "url", url, "err", err)
 		return nil, fmt.Errorf("error making request: %w", err)
 	}
 	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP error! status: %d", resp.StatusCode)
-	}
+	observeUpstreamRequest(req.URL.Host, start, resp.StatusCode)
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("error reading response body: %w", err)
 	}
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseNWSError(resp, body)
+	}
+
 	var result map[string]interface{}
 	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, fmt.Errorf("error parsing JSON: %w", err)
@@ -44,6 +83,28 @@ func makeNWSRequest(url string) (map[string]interface{}, error) {
 	return result, nil
 }
 
+// parseNWSError builds an *NWSError from a non-2xx response, decoding the
+// body as RFC 7807 problem-details when the content type says to, and
+// falling back to a generic title derived from the status code otherwise.
+func parseNWSError(resp *http.Response, body []byte) *NWSError {
+	nwsErr := &NWSError{Status: resp.StatusCode}
+
+	if strings.Contains(resp.Header.Get("Content-Type"), "application/problem+json") {
+		var problem problemDetails
+		if err := json.Unmarshal(body, &problem); err == nil {
+			nwsErr.Type = problem.Type
+			nwsErr.Title = problem.Title
+			nwsErr.Detail = problem.Detail
+		}
+	}
+
+	if nwsErr.Title == "" {
+		nwsErr.Title = fmt.Sprintf("HTTP error! status: %d", resp.StatusCode)
+	}
+
+	return nwsErr
+}
+
 // AlertFeature represents a weather alert feature
 type AlertFeature struct {
 	Properties struct {
@@ -55,31 +116,29 @@ type AlertFeature struct {
 	} `json:"properties"`
 }
 
-// FormatAlert formats an alert feature for display
-func FormatAlert(feature AlertFeature) string {
-	props := feature.Properties
-
-	event := props.Event
+// FormatAlert formats a normalized alert for display.
+func FormatAlert(alert Alert) string {
+	event := alert.Event
 	if event == "" {
 		event = "Unknown"
 	}
 
-	area := props.AreaDesc
+	area := alert.Area
 	if area == "" {
 		area = "Unknown"
 	}
 
-	severity := props.Severity
+	severity := alert.Severity
 	if severity == "" {
 		severity = "Unknown"
 	}
 
-	status := props.Status
+	status := alert.Status
 	if status == "" {
 		status = "Unknown"
 	}
 
-	headline := props.Headline
+	headline := alert.Headline
 	if headline == "" {
 		headline = "No headline"
 	}
@@ -96,12 +155,13 @@ func FormatAlert(feature AlertFeature) string {
 
 // ForecastPeriod represents a single period in a weather forecast
 type ForecastPeriod struct {
-	Name            string `json:"name"`
-	Temperature     int    `json:"temperature"`
-	TemperatureUnit string `json:"temperatureUnit"`
-	WindSpeed       string `json:"windSpeed"`
-	WindDirection   string `json:"windDirection"`
-	ShortForecast   string `json:"shortForecast"`
+	Name             string `json:"name"`
+	Temperature      int    `json:"temperature"`
+	TemperatureUnit  string `json:"temperatureUnit"`
+	WindSpeed        string `json:"windSpeed"`
+	WindDirection    string `json:"windDirection"`
+	ShortForecast    string `json:"shortForecast"`
+	DetailedForecast string `json:"detailedForecast"`
 }
 
 // AlertsResponse represents the response from the alerts endpoint
@@ -109,10 +169,31 @@ type AlertsResponse struct {
 	Features []AlertFeature `json:"features"`
 }
 
+// Point represents a resolved NWS grid point for a set of coordinates
+type Point struct {
+	GridID         string
+	GridX          int
+	GridY          int
+	Forecast       string
+	ForecastHourly string
+	City           string
+	State          string
+}
+
 // PointsResponse represents the response from the points endpoint
 type PointsResponse struct {
 	Properties struct {
-		Forecast string `json:"forecast"`
+		GridID           string `json:"gridId"`
+		GridX            int    `json:"gridX"`
+		GridY            int    `json:"gridY"`
+		Forecast         string `json:"forecast"`
+		ForecastHourly   string `json:"forecastHourly"`
+		RelativeLocation struct {
+			Properties struct {
+				City  string `json:"city"`
+				State string `json:"state"`
+			} `json:"properties"`
+		} `json:"relativeLocation"`
 	} `json:"properties"`
 }
 
@@ -124,8 +205,8 @@ type ForecastResponse struct {
 }
 
 // GetAlerts fetches weather alerts for a given area
-func GetAlerts(url string) ([]AlertFeature, error) {
-	data, err := makeNWSRequest(url)
+func GetAlerts(ctx context.Context, url string) ([]AlertFeature, error) {
+	data, err := makeNWSRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -143,15 +224,18 @@ func GetAlerts(url string) ([]AlertFeature, error) {
 	return alertsResp.Features, nil
 }
 
-// GetForecast fetches forecast data for a given location
-func GetForecast(pointsURL string) ([]ForecastPeriod, error) {
-	// First get the forecast URL from the points endpoint
-	pointsData, err := makeNWSRequest(pointsURL)
+// Points resolves the NWS grid point for a set of coordinates, which carries
+// the forecast URLs and human-readable location needed by GetForecast and
+// GetForecastHourly.
+func Points(ctx context.Context, lat, lng float32) (*Point, error) {
+	pointsURL := fmt.Sprintf("https://api.weather.gov/points/%.4f,%.4f", lat, lng)
+
+	data, err := makeNWSRequest(ctx, pointsURL)
 	if err != nil {
 		return nil, err
 	}
 
-	jsonData, err := json.Marshal(pointsData)
+	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return nil, fmt.Errorf("error re-marshaling points data: %w", err)
 	}
@@ -165,13 +249,36 @@ func GetForecast(pointsURL string) ([]ForecastPeriod, error) {
 		return nil, fmt.Errorf("no forecast URL found in points response")
 	}
 
-	// Now get the forecast data
-	forecastData, err := makeNWSRequest(pointsResp.Properties.Forecast)
+	return &Point{
+		GridID:         pointsResp.Properties.GridID,
+		GridX:          pointsResp.Properties.GridX,
+		GridY:          pointsResp.Properties.GridY,
+		Forecast:       pointsResp.Properties.Forecast,
+		ForecastHourly: pointsResp.Properties.ForecastHourly,
+		City:           pointsResp.Properties.RelativeLocation.Properties.City,
+		State:          pointsResp.Properties.RelativeLocation.Properties.State,
+	}, nil
+}
+
+// GetForecast fetches the forecast periods at the given forecast URL, as
+// resolved by Points.
+func GetForecast(ctx context.Context, forecastURL string) ([]ForecastPeriod, error) {
+	return getForecastPeriods(ctx, forecastURL)
+}
+
+// GetForecastHourly fetches the hourly forecast periods at the given
+// forecast-hourly URL, as resolved by Points.
+func GetForecastHourly(ctx context.Context, forecastHourlyURL string) ([]ForecastPeriod, error) {
+	return getForecastPeriods(ctx, forecastHourlyURL)
+}
+
+func getForecastPeriods(ctx context.Context, forecastURL string) ([]ForecastPeriod, error) {
+	forecastData, err := makeNWSRequest(ctx, forecastURL)
 	if err != nil {
 		return nil, err
 	}
 
-	jsonData, err = json.Marshal(forecastData)
+	jsonData, err := json.Marshal(forecastData)
 	if err != nil {
 		return nil, fmt.Errorf("error re-marshaling forecast data: %w", err)
 	}
@@ -183,3 +290,15 @@ func GetForecast(pointsURL string) ([]ForecastPeriod, error) {
 
 	return forecastResp.Properties.Periods, nil
 }
+
+// FormatForecastPeriod formats a forecast period for display.
+func FormatForecastPeriod(period ForecastPeriod) string {
+	return strings.Join([]string{
+		fmt.Sprintf("%s:", period.Name),
+		fmt.Sprintf("Temperature: %d%s", period.Temperature, period.TemperatureUnit),
+		fmt.Sprintf("Wind: %s %s", period.WindSpeed, period.WindDirection),
+		fmt.Sprintf("Forecast: %s", period.ShortForecast),
+		period.DetailedForecast,
+		"---",
+	}, "\n")
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseNWSError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		wantTitle   string
+		wantDetail  string
+	}{
+		{
+			name:        "problem details decoded",
+			status:      http.StatusNotFound,
+			contentType: "application/problem+json",
+			body:        `{"type":"https://api.weather.gov/problems/NotFound","title":"Not Found","detail":"No grid point found for coordinates"}`,
+			wantTitle:   "Not Found",
+			wantDetail:  "No grid point found for coordinates",
+		},
+		{
+			name:        "non-problem content type falls back to generic title",
+			status:      http.StatusInternalServerError,
+			contentType: "text/plain",
+			body:        "something went wrong",
+			wantTitle:   "HTTP error! status: 500",
+		},
+		{
+			name:        "problem content type with unparsable body falls back to generic title",
+			status:      http.StatusBadGateway,
+			contentType: "application/problem+json",
+			body:        "not json",
+			wantTitle:   "HTTP error! status: 502",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: tt.status,
+				Header:     http.Header{"Content-Type": []string{tt.contentType}},
+			}
+
+			err := parseNWSError(resp, []byte(tt.body))
+
+			if err.Status != tt.status {
+				t.Errorf("Status = %d, want %d", err.Status, tt.status)
+			}
+			if err.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", err.Title, tt.wantTitle)
+			}
+			if err.Detail != tt.wantDetail {
+				t.Errorf("Detail = %q, want %q", err.Detail, tt.wantDetail)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// hourlyForecaster is an optional capability some WeatherProviders (NWS)
+// support for the "hourly" argument on get-forecast.
+type hourlyForecaster interface {
+	ForecastByCoordsHourly(ctx context.Context, lat, lng float64) (*Forecast, error)
+}
+
+// NewWeatherTools builds the get-alerts and get-forecast tools on top of the
+// given providers, tried in order. This is how NWS (US-only, no key) and
+// OpenWeatherMap (worldwide, needs OWM_API_KEY) are wired together: NWS goes
+// first and OWM is only reached once NWS reports it has no coverage for the
+// area, so new backends can be added here without the tool handlers
+// changing.
+func NewWeatherTools(providers ...WeatherProvider) []Tool {
+	return []Tool{
+		newAlertsTool(providers),
+		newForecastTool(providers),
+	}
+}
+
+func newAlertsTool(providers []WeatherProvider) Tool {
+	return Tool{
+		Name:        "get-alerts",
+		Description: "Get weather alerts for a state",
+		InputSchema: ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"state": map[string]interface{}{
+					"type":        "string",
+					"description": "The state to get weather alerts for",
+				},
+			},
+			Required: []string{"state"},
+		},
+		Handler: func(ctx context.Context, args ToolRunParams) (*ToolResult, error) {
+			state := args.Args["state"].(string)
+
+			var alerts []Alert
+			var err error
+			var errs []error
+			for _, p := range providers {
+				area := state
+				if lat, lng, gerr := p.Geocode(ctx, state); gerr == nil {
+					area = fmt.Sprintf("%.4f,%.4f", lat, lng)
+				}
+
+				alerts, err = p.Alerts(ctx, area)
+				if err == nil {
+					break
+				}
+				errs = append(errs, err)
+			}
+
+			if err != nil {
+				slog.Error("error getting alerts", "Err", err)
+				return &ToolResult{
+					Content: []TextContent{
+						{Type: "text", Text: nwsErrorMessage(errs, "no alerts data for "+state)},
+					},
+				}, nil
+			}
+
+			if len(alerts) == 0 {
+				return &ToolResult{
+					Content: []TextContent{
+						{Type: "text", Text: "No active alerts for " + state},
+					},
+				}, nil
+			}
+
+			formattedAlerts := make([]string, len(alerts))
+			for i, a := range alerts {
+				formattedAlerts[i] = FormatAlert(a)
+			}
+
+			alertText := fmt.Sprintf("Active alerts for %s:\n\n%s", state, strings.Join(formattedAlerts, "\n"))
+
+			return &ToolResult{
+				Content: []TextContent{
+					{Type: "text", Text: alertText},
+				},
+			}, nil
+		},
+	}
+}
+
+func newForecastTool(providers []WeatherProvider) Tool {
+	return Tool{
+		Name:        "get-forecast",
+		Description: "Get the weather forecast for a set of coordinates",
+		InputSchema: ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"latitude": map[string]interface{}{
+					"type":        "number",
+					"description": "Latitude of the location",
+				},
+				"longitude": map[string]interface{}{
+					"type":        "number",
+					"description": "Longitude of the location",
+				},
+				"hourly": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Whether to return an hourly forecast instead of the standard period forecast",
+				},
+			},
+			Required: []string{"latitude", "longitude"},
+		},
+		Handler: func(ctx context.Context, args ToolRunParams) (*ToolResult, error) {
+			lat := args.Args["latitude"].(float64)
+			lng := args.Args["longitude"].(float64)
+			hourly, _ := args.Args["hourly"].(bool)
+
+			var forecast *Forecast
+			var err error
+			var errs []error
+			hourlyHonored := false
+			for _, p := range providers {
+				if hourly {
+					if hp, ok := p.(hourlyForecaster); ok {
+						forecast, err = hp.ForecastByCoordsHourly(ctx, lat, lng)
+						if err == nil {
+							hourlyHonored = true
+							break
+						}
+						errs = append(errs, err)
+						continue
+					}
+				}
+				forecast, err = p.ForecastByCoords(ctx, lat, lng)
+				if err == nil {
+					break
+				}
+				errs = append(errs, err)
+			}
+
+			if err != nil {
+				slog.Error("error getting forecast", "Err", err)
+				return &ToolResult{
+					Content: []TextContent{
+						{Type: "text", Text: nwsErrorMessage(errs, "no forecast data for these coordinates")},
+					},
+				}, nil
+			}
+
+			if len(forecast.Periods) == 0 {
+				return &ToolResult{
+					Content: []TextContent{
+						{Type: "text", Text: "No forecast periods available for this location"},
+					},
+				}, nil
+			}
+
+			formattedPeriods := make([]string, len(forecast.Periods))
+			for i, p := range forecast.Periods {
+				formattedPeriods[i] = FormatForecastPeriod(p)
+			}
+
+			location := forecast.Location
+			if location == "" {
+				location = fmt.Sprintf("%.4f, %.4f", lat, lng)
+			}
+
+			forecastText := fmt.Sprintf("Forecast for %s:\n\n%s", location, strings.Join(formattedPeriods, "\n"))
+			if hourly && !hourlyHonored {
+				forecastText = "Hourly data unavailable, showing the standard forecast instead:\n\n" + forecastText
+			}
+
+			return &ToolResult{
+				Content: []TextContent{
+					{Type: "text", Text: forecastText},
+				},
+			}, nil
+		},
+	}
+}